@@ -9,30 +9,26 @@ import (
 	"log"
 
 	"github.com/wemixarchive/vrf-generator/crypto/vrf"
+	"github.com/wemixarchive/vrf-generator/crypto/vrf/keystore"
 )
 
 func main() {
 	// Define and parse command-line flags
-	privateKeyHex := flag.String("privateKey", "", "Private key in hexadecimal format (required)")
+	keystorePath := flag.String("keystore", "", "Path to an encrypted keystore file; takes precedence over -privateKey")
+	passphrase := flag.String("passphrase", "", "Passphrase for -keystore")
+	privateKeyHex := flag.String("privateKey", "", "Private key in hexadecimal format (required unless -keystore is set)")
 	message := flag.String("message", "", "Message to prove (required)")
 	flag.Parse()
 
-	// Check if both privateKey and message are provided
-	if *privateKeyHex == "" || *message == "" {
+	// Check if a key source and message are provided
+	if (*keystorePath == "" && *privateKeyHex == "") || *message == "" {
 		flag.PrintDefaults()
 		return
 	}
 
-	// Decode private key from hexadecimal
-	privateKeyBytes, err := hex.DecodeString(*privateKeyHex)
+	pk, sk, err := loadKey(*keystorePath, *passphrase, *privateKeyHex)
 	if err != nil {
-		log.Fatalf("Error decoding private key: %v", err)
-	}
-
-	// Generate public key from private key
-	pk, sk, err := ed25519.GenerateKey(bytes.NewReader(privateKeyBytes))
-	if err != nil {
-		log.Fatalf("Error generating public key: %v", err)
+		log.Fatalf("Error loading key: %v", err)
 	}
 	// fmt.Println(">>> pk:", len(pk), hex.EncodeToString(pk)) // 32
 	// fmt.Println(">>> sk:", len(sk), hex.EncodeToString(sk)) // 64
@@ -47,3 +43,21 @@ func main() {
 	fmt.Printf("Proof: %x\n", proof)
 	fmt.Printf("Hash: %x\n", hash)
 }
+
+// loadKey resolves the keypair to prove with, preferring an encrypted
+// keystore over a raw hex private key when both are set.
+func loadKey(keystorePath, passphrase, privateKeyHex string) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	if keystorePath != "" {
+		sk, err := keystore.LoadKey(keystorePath, passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sk.Public().(ed25519.PublicKey), sk, nil
+	}
+
+	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding private key: %w", err)
+	}
+	return ed25519.GenerateKey(bytes.NewReader(privateKeyBytes))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/wemixarchive/vrf-generator/crypto/vrf/keystore"
+	"github.com/wemixarchive/vrf-generator/crypto/vrf/server"
+)
+
+func main() {
+	keystorePath := flag.String("keystore", "", "Path to an encrypted keystore file; takes precedence over -privateKey")
+	passphrase := flag.String("passphrase", "", "Passphrase for -keystore")
+	privateKeyHex := flag.String("privateKey", "", "Private key seed in hexadecimal format (required unless -keystore is set)")
+	addr := flag.String("addr", ":8080", "Address to listen on")
+	tlsCert := flag.String("tls-cert", "", "Path to a TLS certificate; if set with -tls-key, serve over HTTPS")
+	tlsKey := flag.String("tls-key", "", "Path to a TLS private key; if set with -tls-cert, serve over HTTPS")
+	authToken := flag.String("auth-token", "", "If set, require \"Authorization: Bearer <token>\" on every request")
+	flag.Parse()
+
+	if *keystorePath == "" && *privateKeyHex == "" {
+		flag.PrintDefaults()
+		return
+	}
+
+	// Keys are loaded once at startup and kept in memory for the life
+	// of the process; there is no per-request key lookup.
+	pk, sk, err := loadKey(*keystorePath, *passphrase, *privateKeyHex)
+	if err != nil {
+		log.Fatalf("Error loading key: %v", err)
+	}
+
+	handler := server.NewHandler(pk, sk, *authToken)
+
+	if *tlsCert != "" && *tlsKey != "" {
+		log.Printf("vrf-server listening on %s (TLS)", *addr)
+		log.Fatal(http.ListenAndServeTLS(*addr, *tlsCert, *tlsKey, handler))
+	}
+
+	log.Printf("vrf-server listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
+
+// loadKey resolves the keypair to serve, preferring an encrypted
+// keystore over a raw hex private key when both are set.
+func loadKey(keystorePath, passphrase, privateKeyHex string) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	if keystorePath != "" {
+		sk, err := keystore.LoadKey(keystorePath, passphrase)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sk.Public().(ed25519.PublicKey), sk, nil
+	}
+
+	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding private key: %w", err)
+	}
+	return ed25519.GenerateKey(bytes.NewReader(privateKeyBytes))
+}
@@ -0,0 +1,149 @@
+package vrf
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"math/big"
+
+	"github.com/yoseplee/vrf/edwards25519"
+)
+
+// BatchItem is a single (public key, proof, message) tuple to check in a
+// VerifyBatch call.
+type BatchItem struct {
+	PK    ed25519.PublicKey
+	Pi    []byte
+	Alpha []byte
+}
+
+// legacyProofSize is the exact encoded length of a Prove proof: a sign
+// byte, the Gamma point, the challenge and the response. decodeProof
+// indexes into pi with no bounds checks, so itemChallengeDiff must
+// reject anything else before calling it instead of letting a
+// malformed batch item panic the whole call -- the same defect class
+// fixed for the HTTP server's handleVerify.
+const legacyProofSize = 1 + 2*N2 + N
+
+// VerifyBatch verifies many (pk, pi, msg) tuples at once, for workloads
+// such as leader-election or block validation that ingest many VRF
+// outputs together.
+//
+// Each item still needs its own Fiat-Shamir challenge c2_i recomputed
+// from its own U_i, V_i, since this scheme re-derives the challenge
+// from the proof rather than checking a caller-supplied commitment
+// point. What VerifyBatch batches is the pass/fail decision itself: it
+// draws a random scalar z_i (crypto/rand) per item and checks
+//
+//	sum(z_i * (c2_i - c_i))  ==  0  (mod q)
+//
+// in one combined comparison, instead of comparing c2_i to c_i one
+// item at a time. If every proof is valid, every (c2_i - c_i) term is
+// already 0 and the combined sum is trivially 0; if any proof is
+// invalid, a uniformly random z_i makes the combined sum land on 0 by
+// accident with probability at most 1/q (Schwartz-Zippel), negligible
+// for the ~2^252 group order q used here. If the combined check fails,
+// VerifyBatch falls back to the per-item differences it already
+// computed -- no re-verification needed -- to report exactly which
+// items are bad.
+//
+// Computing each item's U_i, V_i is also faster here than in a loop of
+// Verify calls: U_i = c_i*P_i + s_i*G already uses the vartime,
+// windowed edwards25519.GeDoubleScalarMultVartime, but V_i =
+// Gamma_i^c_i * H(m)_i^s_i involves two arbitrary (non-base) points, so
+// Verify falls back to two slow constant-time geScalarMult calls and a
+// geAdd for it. shamirDoubleScalarMult computes both terms of V_i in a
+// single vartime double-and-add pass shared between the two scalars --
+// the genuine source of VerifyBatch's speedup over calling Verify in a
+// loop.
+func VerifyBatch(items []BatchItem) ([]bool, error) {
+	diffs := make([]*big.Int, len(items))
+	for i, it := range items {
+		diffs[i] = itemChallengeDiff(it)
+	}
+
+	combined := big.NewInt(0)
+	allComputed := true
+	for _, d := range diffs {
+		if d == nil {
+			allComputed = false
+			break
+		}
+		z, err := rand.Int(rand.Reader, q)
+		if err != nil {
+			return nil, err
+		}
+		combined.Add(combined, new(big.Int).Mul(z, d))
+	}
+
+	ok := make([]bool, len(items))
+	if allComputed && combined.Mod(combined, q).Sign() == 0 {
+		for i := range ok {
+			ok[i] = true
+		}
+		return ok, nil
+	}
+
+	for i, d := range diffs {
+		ok[i] = d != nil && d.Sign() == 0
+	}
+	return ok, nil
+}
+
+// itemChallengeDiff decodes and re-verifies a single batch item,
+// returning c2_i - c_i mod q (zero iff the item's proof is valid), or
+// nil if the item's public key or proof is too malformed to evaluate.
+func itemChallengeDiff(it BatchItem) *big.Int {
+	if len(it.PK) != ed25519.PublicKeySize {
+		return nil
+	}
+	if len(it.Pi) != legacyProofSize {
+		return nil
+	}
+	r, c, s, err := decodeProof(it.Pi)
+	if err != nil {
+		return nil
+	}
+	P := os2ECP(it.PK, it.PK[31]>>7)
+	if P == nil {
+		return nil
+	}
+	h := hashToCurve(it.Alpha, it.PK)
+
+	var u edwards25519.ProjectiveGroupElement
+	edwards25519.GeDoubleScalarMultVartime(&u, c, P, s)
+	v := shamirDoubleScalarMult(r, c, h, s)
+
+	c2 := hashPoints(ecp2OS(g), ecp2OS(h), s2OS(it.PK), ecp2OS(r), ecp2OSProj(&u), ecp2OS(v))
+	diff := new(big.Int).Sub(c2, f2IP(c))
+	return diff.Mod(diff, q)
+}
+
+// shamirDoubleScalarMult computes a*A + b*B for two arbitrary (not
+// necessarily base) points A and B, via Shamir's trick: a single
+// double-and-add pass shared between both scalars, adding A, B or
+// their precomputed sum only when the corresponding bits are set and
+// skipping the addition entirely otherwise. This is vartime -- both
+// inputs here are public (a proof and a hash-to-curve point) -- and
+// does about half the doublings, and fewer additions on average, than
+// computing a*A and b*B with geScalarMult separately and adding the
+// results.
+func shamirDoubleScalarMult(A *edwards25519.ExtendedGroupElement, a *[32]byte, B *edwards25519.ExtendedGroupElement, b *[32]byte) *edwards25519.ExtendedGroupElement {
+	ab := geAdd(A, B)
+
+	acc := new(edwards25519.ExtendedGroupElement)
+	acc.Zero()
+	for i := 255; i >= 0; i-- {
+		acc = geDouble(acc)
+		abit := int32(a[i>>3]>>(uint(i)&7)) & 1
+		bbit := int32(b[i>>3]>>(uint(i)&7)) & 1
+		switch {
+		case abit == 1 && bbit == 1:
+			acc = geAdd(acc, ab)
+		case abit == 1:
+			acc = geAdd(acc, A)
+		case bbit == 1:
+			acc = geAdd(acc, B)
+		}
+	}
+	return acc
+}
@@ -0,0 +1,124 @@
+package vrf
+
+import (
+	"crypto/ed25519"
+	"testing"
+)
+
+func makeBatchItem(t *testing.T, alpha []byte) BatchItem {
+	t.Helper()
+	pk, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pi, _, err := Prove(pk, sk, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return BatchItem{PK: pk, Pi: pi, Alpha: alpha}
+}
+
+func TestVerifyBatchAllValid(t *testing.T) {
+	items := make([]BatchItem, 5)
+	for i := range items {
+		items[i] = makeBatchItem(t, []byte("batch message"))
+	}
+
+	ok, err := VerifyBatch(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ok) != len(items) {
+		t.Fatalf("len(ok) = %d, want %d", len(ok), len(items))
+	}
+	for i, valid := range ok {
+		if !valid {
+			t.Errorf("item %d: expected valid proof to verify", i)
+		}
+	}
+}
+
+func TestVerifyBatchPinpointsFailure(t *testing.T) {
+	items := make([]BatchItem, 4)
+	for i := range items {
+		items[i] = makeBatchItem(t, []byte("batch message"))
+	}
+	// Corrupt a single item's message so only that entry fails.
+	items[2].Alpha = []byte("tampered message")
+
+	ok, err := VerifyBatch(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, valid := range ok {
+		want := i != 2
+		if valid != want {
+			t.Errorf("item %d: VerifyBatch = %v, want %v", i, valid, want)
+		}
+	}
+}
+
+// TestVerifyBatchRejectsMalformedProof confirms a too-short or empty Pi
+// is reported as an invalid item rather than panicking decodeProof,
+// which indexes/slices pi with no bounds checks of its own.
+func TestVerifyBatchRejectsMalformedProof(t *testing.T) {
+	good := makeBatchItem(t, []byte("batch message"))
+	short := makeBatchItem(t, []byte("batch message"))
+	short.Pi = []byte{2, 1, 2, 3}
+	empty := makeBatchItem(t, []byte("batch message"))
+	empty.Pi = nil
+
+	items := []BatchItem{good, short, empty}
+	ok, err := VerifyBatch(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bool{true, false, false}
+	for i, valid := range ok {
+		if valid != want[i] {
+			t.Errorf("item %d: VerifyBatch = %v, want %v", i, valid, want[i])
+		}
+	}
+}
+
+func makeBatchItems(b *testing.B, n int) []BatchItem {
+	b.Helper()
+	items := make([]BatchItem, n)
+	for i := range items {
+		pk, sk, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pi, _, err := Prove(pk, sk, []byte("batch message"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		items[i] = BatchItem{PK: pk, Pi: pi, Alpha: []byte("batch message")}
+	}
+	return items
+}
+
+// BenchmarkVerifyBatch and BenchmarkVerifyLoop measure VerifyBatch
+// against the naive alternative it claims to beat: calling Verify on
+// each item in a loop.
+func BenchmarkVerifyBatch(b *testing.B) {
+	items := makeBatchItems(b, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := VerifyBatch(items); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyLoop(b *testing.B) {
+	items := makeBatchItems(b, 50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, it := range items {
+			if _, err := Verify(it.PK, it.Pi, it.Alpha); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package keystore
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+const pemBlockType = "VRF PRIVATE KEY"
+
+// ErrMalformedKey is returned by ImportPEM and ImportJWK when the input
+// does not decode to an Ed25519 seed of the expected size.
+var ErrMalformedKey = errors.New("keystore: malformed key")
+
+// ExportPEM encodes sk's seed as a PEM block, for interop with tools
+// that expect a PEM-armored key rather than this package's JSON
+// envelope.
+func ExportPEM(sk ed25519.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  pemBlockType,
+		Bytes: sk.Seed(),
+	})
+}
+
+// ImportPEM decodes a PEM block produced by ExportPEM back into a
+// keypair.
+func ImportPEM(data []byte) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemBlockType {
+		return nil, nil, ErrMalformedKey
+	}
+	if len(block.Bytes) != ed25519.SeedSize {
+		return nil, nil, ErrMalformedKey
+	}
+	sk := ed25519.NewKeyFromSeed(block.Bytes)
+	return sk.Public().(ed25519.PublicKey), sk, nil
+}
+
+// jwk is an RFC 8037 Octet Key Pair JSON Web Key for Ed25519.
+type jwk struct {
+	KTY string `json:"kty"`
+	CRV string `json:"crv"`
+	X   string `json:"x"`
+	D   string `json:"d,omitempty"`
+}
+
+// ExportJWK encodes sk as an RFC 8037 OKP JWK, including the private
+// "d" member so the key can round-trip through ImportJWK.
+func ExportJWK(sk ed25519.PrivateKey) ([]byte, error) {
+	pub, ok := sk.Public().(ed25519.PublicKey)
+	if !ok {
+		return nil, ErrMalformedKey
+	}
+	key := jwk{
+		KTY: "OKP",
+		CRV: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(pub),
+		D:   base64.RawURLEncoding.EncodeToString(sk.Seed()),
+	}
+	return json.Marshal(key)
+}
+
+// ImportJWK decodes an RFC 8037 OKP JWK produced by ExportJWK (or any
+// compliant Ed25519 JWK carrying a private "d" member) into a keypair.
+func ImportJWK(data []byte) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	var key jwk
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, nil, fmt.Errorf("keystore: parsing jwk: %w", err)
+	}
+	if key.KTY != "OKP" || key.CRV != "Ed25519" {
+		return nil, nil, ErrMalformedKey
+	}
+	if key.D == "" {
+		return nil, nil, ErrMalformedKey
+	}
+	seed, err := base64.RawURLEncoding.DecodeString(key.D)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, nil, ErrMalformedKey
+	}
+	sk := ed25519.NewKeyFromSeed(seed)
+
+	pub, err := base64.RawURLEncoding.DecodeString(key.X)
+	if err != nil || !sk.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(pub)) {
+		return nil, nil, ErrMalformedKey
+	}
+	return sk.Public().(ed25519.PublicKey), sk, nil
+}
@@ -0,0 +1,164 @@
+// Package keystore stores VRF private keys on disk as passphrase
+// encrypted JSON, and converts between that format and PEM / RFC 8037
+// JWK for interop with other tooling.
+package keystore
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Scrypt parameters used for every key written by this package: N =
+// 2^17, r = 8, p = 1, a reasonable default for an interactive CLI
+// rather than a server unlocking many keys per second.
+const (
+	scryptN      = 1 << 17
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltSize     = 16
+)
+
+const envelopeVersion = 1
+
+var (
+	// ErrUnsupportedKDF is returned by LoadKey when the envelope names
+	// a KDF this package does not implement.
+	ErrUnsupportedKDF = errors.New("keystore: unsupported kdf")
+	// ErrUnsupportedCipher is returned by LoadKey when the envelope
+	// names a cipher this package does not implement.
+	ErrUnsupportedCipher = errors.New("keystore: unsupported cipher")
+	// ErrDecryptionFailed is returned by LoadKey when the passphrase
+	// is wrong or the envelope has been tampered with.
+	ErrDecryptionFailed = errors.New("keystore: decryption failed (wrong passphrase or corrupt file)")
+)
+
+type kdfParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+// envelope is the on-disk JSON format for an encrypted key.
+type envelope struct {
+	Version    int       `json:"version"`
+	KDF        string    `json:"kdf"`
+	KDFParams  kdfParams `json:"kdfparams"`
+	Cipher     string    `json:"cipher"`
+	Ciphertext string    `json:"ciphertext"`
+	Nonce      string    `json:"nonce"`
+	MAC        string    `json:"mac"`
+}
+
+// SaveKey encrypts sk's seed with passphrase and writes it to path as a
+// JSON envelope.
+func SaveKey(path string, sk ed25519.PrivateKey, passphrase string) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return fmt.Errorf("keystore: deriving key: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return fmt.Errorf("keystore: %w", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	sealed := aead.Seal(nil, nonce, sk.Seed(), nil)
+	tagStart := len(sealed) - aead.Overhead()
+	ciphertext, mac := sealed[:tagStart], sealed[tagStart:]
+
+	env := envelope{
+		Version: envelopeVersion,
+		KDF:     "scrypt",
+		KDFParams: kdfParams{
+			N:    scryptN,
+			R:    scryptR,
+			P:    scryptP,
+			Salt: hex.EncodeToString(salt),
+		},
+		Cipher:     "xchacha20poly1305",
+		Ciphertext: hex.EncodeToString(ciphertext),
+		Nonce:      hex.EncodeToString(nonce),
+		MAC:        hex.EncodeToString(mac),
+	}
+
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// LoadKey reads the JSON envelope at path and decrypts it with
+// passphrase, returning the private key it encodes. The corresponding
+// public key is derivable from it via sk.Public().
+func LoadKey(path, passphrase string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("keystore: parsing envelope: %w", err)
+	}
+	if env.KDF != "scrypt" {
+		return nil, ErrUnsupportedKDF
+	}
+	if env.Cipher != "xchacha20poly1305" {
+		return nil, ErrUnsupportedCipher
+	}
+
+	salt, err := hex.DecodeString(env.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: malformed salt: %w", err)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, env.KDFParams.N, env.KDFParams.R, env.KDFParams.P, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: deriving key: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: %w", err)
+	}
+
+	nonce, err := hex.DecodeString(env.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: malformed nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(env.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: malformed ciphertext: %w", err)
+	}
+	mac, err := hex.DecodeString(env.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("keystore: malformed mac: %w", err)
+	}
+
+	seed, err := aead.Open(nil, nonce, append(ciphertext, mac...), nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
@@ -0,0 +1,77 @@
+package keystore
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadKeyRoundTrip(t *testing.T) {
+	_, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := SaveKey(path, sk, "correct horse battery staple"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadKey(path, "correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Equal(sk) {
+		t.Fatal("loaded key does not match the saved key")
+	}
+}
+
+func TestLoadKeyWrongPassphrase(t *testing.T) {
+	_, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := SaveKey(path, sk, "correct horse battery staple"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadKey(path, "wrong passphrase"); err != ErrDecryptionFailed {
+		t.Fatalf("err = %v, want %v", err, ErrDecryptionFailed)
+	}
+}
+
+func TestPEMRoundTrip(t *testing.T) {
+	_, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pk, loaded, err := ImportPEM(ExportPEM(sk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Equal(sk) || !pk.Equal(sk.Public().(ed25519.PublicKey)) {
+		t.Fatal("PEM round trip did not preserve the key")
+	}
+}
+
+func TestJWKRoundTrip(t *testing.T) {
+	_, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ExportJWK(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, loaded, err := ImportJWK(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Equal(sk) || !pk.Equal(sk.Public().(ed25519.PublicKey)) {
+		t.Fatal("JWK round trip did not preserve the key")
+	}
+}
@@ -0,0 +1,333 @@
+package vrf
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"math/big"
+
+	"github.com/yoseplee/vrf/edwards25519"
+)
+
+// Suite identifies a VRF ciphersuite. The zero value selects the legacy
+// try-and-increment construction implemented by Prove/Verify/Hash, kept
+// for backward compatibility; ProveSuite/VerifySuite select one of the
+// RFC 9381-shaped suites below.
+//
+// IMPORTANT: ProveSuite/VerifySuite/HashSuite follow RFC 9381's overall
+// structure -- the same suite byte values from the RFC's registry, the
+// same domain-separated hash-to-curve/nonce/challenge/proof_to_hash
+// steps in the same order -- but this package has not been checked
+// against the RFC's own Appendix A.4/B test vectors, so it is NOT
+// verified to be byte-for-byte interoperable with other RFC 9381
+// implementations. Treat ProveSuite/VerifySuite as a self-consistent,
+// RFC-shaped construction, not a certified one; rfc9381_test.go pins
+// fixed inputs as a regression fixture, not a conformance suite.
+type Suite byte
+
+const (
+	// SuiteLegacy is the pre-standard construction implemented by
+	// Prove/Verify in vrf.go. It is not interoperable with other RFC 9381
+	// implementations.
+	SuiteLegacy Suite = 0x00
+
+	// SuiteRFC9381ShapedTAI uses the RFC 9381 registry's
+	// ECVRF-EDWARDS25519-SHA512-TAI suite byte (0x03) and a
+	// try-and-increment hash-to-curve with the RFC's domain separation
+	// bytes and an 80-byte proof encoding. See the Suite doc comment:
+	// not yet verified against the RFC's own test vectors.
+	SuiteRFC9381ShapedTAI Suite = 0x03
+
+	// SuiteRFC9381ShapedELL2 uses the RFC 9381 registry's
+	// ECVRF-EDWARDS25519-SHA512-ELL2 suite byte (0x04) and an
+	// Elligator2 hash-to-curve with cofactor clearing. See the Suite
+	// doc comment: not yet verified against the RFC's own test vectors.
+	SuiteRFC9381ShapedELL2 Suite = 0x04
+)
+
+// rfc9381ProofSize is the length of a Gamma(32) || c(N) || s(N2) proof.
+const rfc9381ProofSize = 32 + N + N2
+
+// ProveSuite computes an RFC 9381-shaped VRF proof for alpha under (pk,
+// sk) using the given ciphersuite. suite must be SuiteRFC9381ShapedTAI
+// or SuiteRFC9381ShapedELL2. See the Suite doc comment for this
+// package's current conformance caveat.
+func ProveSuite(pk ed25519.PublicKey, sk ed25519.PrivateKey, alpha []byte, suite Suite) (pi, hash []byte, err error) {
+	if suite != SuiteRFC9381ShapedTAI && suite != SuiteRFC9381ShapedELL2 {
+		return nil, nil, ErrMalformedInput
+	}
+
+	x := expandSecret(sk)
+	H := hashToCurveSuite(alpha, pk, suite)
+	Gamma := geScalarMult(H, x)
+
+	k := proveNonce(sk, H)
+	U := geScalarMult(g, k)
+	V := geScalarMult(H, k)
+
+	c := challengeGenerate(suite, pk, H, Gamma, U, V)
+	cBig := os2IP(c)
+
+	var z big.Int
+	s := z.Mod(z.Sub(f2IP(k), z.Mul(cBig, f2IP(x))), q)
+
+	pi = append(pi, encodePoint(Gamma)...)
+	pi = append(pi, i2OSP(cBig, N)...)
+	pi = append(pi, i2OSP(s, N2)...)
+
+	return pi, HashSuite(pi, suite), nil
+}
+
+// VerifySuite reports whether pi is a valid RFC 9381-shaped proof for
+// alpha under pk in the given ciphersuite. See the Suite doc comment
+// for this package's current conformance caveat.
+func VerifySuite(pk ed25519.PublicKey, pi, alpha []byte, suite Suite) (bool, error) {
+	if suite != SuiteRFC9381ShapedTAI && suite != SuiteRFC9381ShapedELL2 {
+		return false, ErrMalformedInput
+	}
+	Gamma, c, s, err := decodeProofSuite(pi)
+	if err != nil {
+		return false, err
+	}
+
+	H := hashToCurveSuite(alpha, pk, suite)
+	P := os2ECP(pk, pk[31]>>7)
+	if P == nil {
+		return false, ErrMalformedInput
+	}
+
+	var uProj edwards25519.ProjectiveGroupElement
+	edwards25519.GeDoubleScalarMultVartime(&uProj, c, P, s)
+	var U edwards25519.ExtendedGroupElement
+	var uBytes [32]byte
+	uProj.ToBytes(&uBytes)
+	if !U.FromBytes(&uBytes) {
+		return false, ErrInternalError
+	}
+
+	V := geAdd(geScalarMult(Gamma, c), geScalarMult(H, s))
+
+	c2 := challengeGenerate(suite, pk, H, Gamma, &U, V)
+
+	return os2IP(c2).Cmp(f2IP(c)) == 0, nil
+}
+
+// HashSuite derives the vrf output beta from a proof produced under the
+// given ciphersuite, following RFC 9381's proof_to_hash shape:
+// SHA512(suite_string || 0x03 || cofactor*Gamma || 0x00), truncated to
+// 32 bytes. See the Suite doc comment for this package's current
+// conformance caveat.
+func HashSuite(pi []byte, suite Suite) []byte {
+	Gamma, _, _, err := decodeProofSuite(pi)
+	if err != nil {
+		return nil
+	}
+	cGamma := geScalarMult(Gamma, ip2F(big.NewInt(cofactor)))
+
+	h := sha512.New()
+	h.Write([]byte{byte(suite), 0x03})
+	h.Write(encodePoint(cGamma))
+	h.Write([]byte{0x00})
+	sum := h.Sum(nil)
+	return sum[:32]
+}
+
+// decodeProofSuite splits an RFC 9381 proof pi = Gamma(32) || c(N) || s(N2).
+func decodeProofSuite(pi []byte) (gamma *edwards25519.ExtendedGroupElement, c, s *[32]byte, err error) {
+	if len(pi) != rfc9381ProofSize {
+		return nil, nil, nil, ErrDecodeError
+	}
+	gamma = os2ECP(pi[:32], 2)
+	if gamma == nil {
+		return nil, nil, nil, ErrDecodeError
+	}
+
+	c = new([32]byte)
+	for j, i := N-1, 32; j >= 0; j-- {
+		c[j] = pi[i]
+		i++
+	}
+	s = new([32]byte)
+	for j, i := N2-1, 32+N; j >= 0; j-- {
+		s[j] = pi[i]
+		i++
+	}
+	return gamma, c, s, nil
+}
+
+// encodePoint compresses a curve point in standard Ed25519 form: a
+// 32-byte little-endian y-coordinate with the sign of x in the top bit.
+func encodePoint(p *edwards25519.ExtendedGroupElement) []byte {
+	var s [32]byte
+	p.ToBytes(&s)
+	return s[:]
+}
+
+// challengeGenerate computes c = SHA512(suite_string || 0x02 || pk || H ||
+// Gamma || U || V || 0x00)[:N], per RFC 9381 §5.4.3.
+func challengeGenerate(suite Suite, pk ed25519.PublicKey, H, Gamma, U, V *edwards25519.ExtendedGroupElement) []byte {
+	h := sha512.New()
+	h.Write([]byte{byte(suite), 0x02})
+	h.Write(pk)
+	h.Write(encodePoint(H))
+	h.Write(encodePoint(Gamma))
+	h.Write(encodePoint(U))
+	h.Write(encodePoint(V))
+	h.Write([]byte{0x00})
+	sum := h.Sum(nil)
+	return sum[:N]
+}
+
+// proveNonce derives the deterministic per-proof nonce k, per RFC 9381
+// §5.4.2.2: k = SHA512(sk_hash[32:] || H_string) reduced mod q.
+func proveNonce(sk ed25519.PrivateKey, H *edwards25519.ExtendedGroupElement) *[32]byte {
+	skDigest := sha512.Sum512(sk[:32])
+	h := sha512.New()
+	h.Write(skDigest[32:])
+	h.Write(encodePoint(H))
+	kh := h.Sum(nil)
+
+	kBig := new(big.Int).Mod(new(big.Int).SetBytes(kh), q)
+	return ip2F(kBig)
+}
+
+// hashToCurveSuite dispatches hash-to-curve to the try-and-increment or
+// Elligator2 map, depending on suite.
+func hashToCurveSuite(alpha []byte, pk ed25519.PublicKey, suite Suite) *edwards25519.ExtendedGroupElement {
+	if suite == SuiteRFC9381ShapedELL2 {
+		return hashToCurveElligator2(alpha, pk, suite)
+	}
+	return hashToCurveTAISuite(alpha, pk, suite)
+}
+
+// hashToCurveTAISuite is try-and-increment hash-to-curve using the RFC
+// 9381 domain separation bytes (suite_string || 0x01 || pk || alpha ||
+// ctr || 0x00), unlike the legacy hashToCurve's draft byte layout.
+func hashToCurveTAISuite(alpha []byte, pk ed25519.PublicKey, suite Suite) *edwards25519.ExtendedGroupElement {
+	for ctr := 0; ctr < 256; ctr++ {
+		h := sha512.New()
+		h.Write([]byte{byte(suite), 0x01})
+		h.Write(pk)
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), 0x00})
+		sum := h.Sum(nil)
+
+		var candidate [32]byte
+		copy(candidate[:], sum[:32])
+		if p := os2ECP(candidate[:], candidate[31]>>7); p != nil {
+			return geScalarMult(p, ip2F(big.NewInt(cofactor)))
+		}
+	}
+	panic("vrf: hashToCurveTAISuite: no valid point found in 256 tries")
+}
+
+// hashToCurveElligator2 maps a 32-byte field element derived from
+// (suite_string, pk, alpha) onto the curve via the Elligator2 map, then
+// clears the cofactor by multiplying by 8. Elligator2 is a map onto the
+// whole curve only up to the choice of the x-sign convention; on the rare
+// input where neither sign decodes, a trailing counter byte is folded in
+// and the hash is retried, mirroring hashToCurveTAISuite's retry loop.
+func hashToCurveElligator2(alpha []byte, pk ed25519.PublicKey, suite Suite) *edwards25519.ExtendedGroupElement {
+	for ctr := 0; ctr < 256; ctr++ {
+		h := sha512.New()
+		h.Write([]byte{byte(suite), 0x01})
+		h.Write(pk)
+		h.Write(alpha)
+		if ctr > 0 {
+			h.Write([]byte{byte(ctr)})
+		}
+		sum := h.Sum(nil)
+
+		var r [32]byte
+		copy(r[:], sum[:32])
+		r[31] &= 0x7f
+
+		if p := elligator2Map(&r); p != nil {
+			return geScalarMult(p, ip2F(big.NewInt(cofactor)))
+		}
+	}
+	panic("vrf: hashToCurveElligator2: no valid point found in 256 tries")
+}
+
+// curve25519P is the field prime 2^255 - 19.
+var curve25519P, _ = new(big.Int).SetString("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed", 16)
+
+// montgomeryA is the Montgomery A coefficient of curve25519.
+var montgomeryA = big.NewInt(486662)
+
+// elligator2Map implements the Elligator2-to-Edwards map from RFC 9381
+// §5.5: it interprets r as a Montgomery u-coordinate candidate, picks
+// whichever of {u, -u-A} lands on the curve, and converts the resulting
+// Montgomery point to twisted-Edwards form via the birational map
+// y = (u+1)/(u-1). It returns nil if neither choice of the x-sign
+// decodes to a point, leaving the caller to retry with a fresh r.
+func elligator2Map(r *[32]byte) *edwards25519.ExtendedGroupElement {
+	p := curve25519P
+	one := big.NewInt(1)
+
+	rVal := new(big.Int).Mod(new(big.Int).SetBytes(reverseBytes(r[:])), p)
+
+	r2 := new(big.Int).Mod(new(big.Int).Mul(rVal, rVal), p)
+	den := new(big.Int).Mod(new(big.Int).Add(one, new(big.Int).Lsh(r2, 1)), p)
+	if den.Sign() == 0 {
+		den.SetInt64(1)
+	}
+
+	u := new(big.Int).Mod(new(big.Int).Mul(new(big.Int).Neg(montgomeryA), new(big.Int).ModInverse(den, p)), p)
+
+	if !isSquare(montgomeryRHS(u, p), p) {
+		u = new(big.Int).Mod(new(big.Int).Sub(new(big.Int).Neg(u), montgomeryA), p)
+	}
+
+	denom := new(big.Int).Mod(new(big.Int).Sub(u, one), p)
+	if denom.Sign() == 0 {
+		denom.SetInt64(1)
+	}
+	y := new(big.Int).Mod(new(big.Int).Mul(new(big.Int).Add(u, one), new(big.Int).ModInverse(denom, p)), p)
+
+	yLE := fieldElementBytes(y)
+
+	var point edwards25519.ExtendedGroupElement
+	yLE[31] &= 0x7f
+	if point.FromBytes(&yLE) {
+		return &point
+	}
+	yLE[31] |= 0x80
+	if point.FromBytes(&yLE) {
+		return &point
+	}
+	return nil
+}
+
+// montgomeryRHS returns u^3 + A*u^2 + u mod p.
+func montgomeryRHS(u, p *big.Int) *big.Int {
+	u2 := new(big.Int).Mod(new(big.Int).Mul(u, u), p)
+	u3 := new(big.Int).Mod(new(big.Int).Mul(u2, u), p)
+	au2 := new(big.Int).Mul(montgomeryA, u2)
+	w := new(big.Int).Add(u3, au2)
+	w.Add(w, u)
+	return w.Mod(w, p)
+}
+
+// isSquare reports whether w is a quadratic residue mod p.
+func isSquare(w, p *big.Int) bool {
+	return new(big.Int).ModSqrt(w, p) != nil
+}
+
+// fieldElementBytes encodes a field element as 32 little-endian bytes.
+func fieldElementBytes(x *big.Int) [32]byte {
+	var out [32]byte
+	b := x.Bytes()
+	for i, v := range b {
+		out[len(b)-1-i] = v
+	}
+	return out
+}
+
+// reverseBytes returns a copy of b with byte order reversed.
+func reverseBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i, v := range b {
+		out[len(b)-1-i] = v
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package vrf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+)
+
+// rfc9381TAIVectors reuse the SK/alpha inputs from RFC 9381 Appendix
+// A.4 (example 1 and 2 for ECVRF-EDWARDS25519-SHA512-TAI) as well-known
+// fixed inputs; see the Suite doc comment for why this is a regression
+// fixture (ProveSuite/VerifySuite round-trip on these inputs), not a
+// conformance check against the RFC's own published pi/beta outputs,
+// which this package does not assert against.
+var rfc9381TAIVectors = []struct {
+	sk, alpha string
+}{
+	{
+		sk:    "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		alpha: "",
+	},
+	{
+		sk:    "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		alpha: "72",
+	},
+}
+
+// TestRFC9381TAIProveDeterministic checks that ProveSuite is deterministic
+// and that the resulting proof round-trips through VerifySuite for the
+// TAI ciphersuite.
+func TestRFC9381TAIProveDeterministic(t *testing.T) {
+	for _, v := range rfc9381TAIVectors {
+		seed, err := hex.DecodeString(v.sk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sk := ed25519.NewKeyFromSeed(seed)
+		pk := sk.Public().(ed25519.PublicKey)
+		alpha, err := hex.DecodeString(v.alpha)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pi1, beta1, err := ProveSuite(pk, sk, alpha, SuiteRFC9381ShapedTAI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pi2, beta2, err := ProveSuite(pk, sk, alpha, SuiteRFC9381ShapedTAI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pi1, pi2) || !bytes.Equal(beta1, beta2) {
+			t.Fatalf("ProveSuite is not deterministic for alpha=%q", v.alpha)
+		}
+
+		ok, err := VerifySuite(pk, pi1, alpha, SuiteRFC9381ShapedTAI)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("VerifySuite rejected its own proof for alpha=%q", v.alpha)
+		}
+	}
+}
+
+func TestRFC9381ELL2ProveVerify(t *testing.T) {
+	pk, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := []byte("RFC 9381 ELL2 test vector")
+
+	pi, beta, err := ProveSuite(pk, sk, alpha, SuiteRFC9381ShapedELL2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pi) != 80 {
+		t.Fatalf("pi length = %d, want 80", len(pi))
+	}
+	if len(beta) != 32 {
+		t.Fatalf("beta length = %d, want 32", len(beta))
+	}
+
+	ok, err := VerifySuite(pk, pi, alpha, SuiteRFC9381ShapedELL2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("VerifySuite rejected a valid ELL2 proof")
+	}
+
+	// Tampering with alpha must invalidate the proof.
+	ok, err = VerifySuite(pk, pi, append(alpha, 0x00), SuiteRFC9381ShapedELL2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatalf("VerifySuite accepted a proof for the wrong message")
+	}
+}
+
+func TestRFC9381UnsupportedSuite(t *testing.T) {
+	pk, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ProveSuite(pk, sk, []byte("x"), SuiteLegacy); err == nil {
+		t.Fatal("expected error for unsupported suite")
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func newTestServer(t *testing.T, authToken string) (*Server, ed25519.PublicKey) {
+	t.Helper()
+	pk, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(NewHandler(pk, sk, authToken))
+	t.Cleanup(srv.Close)
+	return srv, pk
+}
+
+func doJSON(t *testing.T, method, url string, body interface{}, authToken string) *http.Response {
+	t.Helper()
+	buf, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+authToken)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestServerProveVerifyPubkeyRoundTrip(t *testing.T) {
+	srv, pk := newTestServer(t, "")
+
+	resp := doJSON(t, http.MethodGet, srv.URL()+"/pubkey", nil, "")
+	defer resp.Body.Close()
+	var pkResp pubkeyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&pkResp); err != nil {
+		t.Fatal(err)
+	}
+	if pkResp.PublicKey != hex.EncodeToString(pk) {
+		t.Fatalf("pubkey = %s, want %s", pkResp.PublicKey, hex.EncodeToString(pk))
+	}
+
+	proveResp := doJSON(t, http.MethodPost, srv.URL()+"/prove", proveRequest{Message: "hello"}, "")
+	defer proveResp.Body.Close()
+	var pr proveResponse
+	if err := json.NewDecoder(proveResp.Body).Decode(&pr); err != nil {
+		t.Fatal(err)
+	}
+	if pr.Proof == "" || pr.Hash == "" {
+		t.Fatal("expected non-empty proof and hash")
+	}
+
+	verifyResp := doJSON(t, http.MethodPost, srv.URL()+"/verify", verifyRequest{
+		PublicKey: pkResp.PublicKey,
+		Proof:     pr.Proof,
+		Message:   "hello",
+	}, "")
+	defer verifyResp.Body.Close()
+	var vr verifyResponse
+	if err := json.NewDecoder(verifyResp.Body).Decode(&vr); err != nil {
+		t.Fatal(err)
+	}
+	if !vr.Valid {
+		t.Fatal("expected proof to verify")
+	}
+}
+
+func TestServerVerifyRejectsWrongMessage(t *testing.T) {
+	srv, pk := newTestServer(t, "")
+
+	pr := doJSON(t, http.MethodPost, srv.URL()+"/prove", proveRequest{Message: "hello"}, "")
+	defer pr.Body.Close()
+	var proveResp proveResponse
+	if err := json.NewDecoder(pr.Body).Decode(&proveResp); err != nil {
+		t.Fatal(err)
+	}
+
+	vr := doJSON(t, http.MethodPost, srv.URL()+"/verify", verifyRequest{
+		PublicKey: hex.EncodeToString(pk),
+		Proof:     proveResp.Proof,
+		Message:   "goodbye",
+	}, "")
+	defer vr.Body.Close()
+	var verifyResp verifyResponse
+	if err := json.NewDecoder(vr.Body).Decode(&verifyResp); err != nil {
+		t.Fatal(err)
+	}
+	if verifyResp.Valid {
+		t.Fatal("expected proof for the wrong message to be rejected")
+	}
+}
+
+// TestServerVerifyRejectsShortPublicKey confirms a truncated publicKey
+// is reported as a clean 400 rather than panicking handleVerify, which
+// indexes pk[31] unconditionally once it reaches vrf.Verify.
+func TestServerVerifyRejectsShortPublicKey(t *testing.T) {
+	srv, _ := newTestServer(t, "")
+
+	pr := doJSON(t, http.MethodPost, srv.URL()+"/prove", proveRequest{Message: "hello"}, "")
+	defer pr.Body.Close()
+	var proveResp proveResponse
+	if err := json.NewDecoder(pr.Body).Decode(&proveResp); err != nil {
+		t.Fatal(err)
+	}
+
+	vr := doJSON(t, http.MethodPost, srv.URL()+"/verify", verifyRequest{
+		PublicKey: "0102",
+		Proof:     proveResp.Proof,
+		Message:   "hello",
+	}, "")
+	defer vr.Body.Close()
+	if vr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", vr.StatusCode, http.StatusBadRequest)
+	}
+}
+
+// TestServerVerifyRejectsShortProof confirms a truncated proof is
+// reported as a clean 400 rather than panicking handleVerify, which
+// would otherwise reach decodeProof's unchecked indexing into pi.
+func TestServerVerifyRejectsShortProof(t *testing.T) {
+	srv, pk := newTestServer(t, "")
+
+	vr := doJSON(t, http.MethodPost, srv.URL()+"/verify", verifyRequest{
+		PublicKey: hex.EncodeToString(pk),
+		Proof:     "0102",
+		Message:   "hello",
+	}, "")
+	defer vr.Body.Close()
+	if vr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", vr.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestServerRequiresBearerToken(t *testing.T) {
+	srv, _ := newTestServer(t, "s3cret")
+
+	resp := doJSON(t, http.MethodGet, srv.URL()+"/pubkey", nil, "")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	authed := doJSON(t, http.MethodGet, srv.URL()+"/pubkey", nil, "s3cret")
+	defer authed.Body.Close()
+	if authed.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", authed.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,138 @@
+package vrf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/yoseplee/vrf/edwards25519"
+)
+
+// ProveSHAKE and VerifySHAKE are a Coname-style (github.com/yahoo/coname/vrf)
+// VRF variant: the same challenge/response construction as Prove and
+// Verify -- c = H(g, H(m), g^x, H(m)^x, g^k, H(m)^k), s = k - c*x mod q
+// -- but with the hash-to-curve and Fiat-Shamir challenge hashes done
+// with SHAKE256 instead of SHA-256. Secret scalars are still expanded
+// with expandSecret (SHA-512, clamped as in the ed25519 standard),
+// since pk/sk here are ordinary crypto/ed25519 keys and P must still
+// equal g^x for whatever x that derivation produces. This package
+// reuses its own edwards25519 arithmetic rather than coname's Elligator
+// map and point format, so its output is not byte-compatible with
+// calling yahoo/coname/vrf directly; see shake_test.go for a cross-check
+// against that reference implementation.
+//
+// The vrf output itself, like coname's vrf = h(ii_bytes, m), is a
+// SHAKE256 digest of the proof's Gamma point (h(m)^x, the "ii" in
+// coname's naming) concatenated with the message -- not the legacy
+// scheme's Hash(pi), which is just the raw, unhashed Gamma bytes
+// sliced out of pi. shakeBeta computes that digest; HashSHAKE exposes
+// it to callers holding just a proof and a message.
+
+// ProveSHAKE generates a vrf output and corresponding proof(pi) with
+// secret key sk, hashing to the curve and to the challenge with
+// SHAKE256 instead of SHA-256.
+func ProveSHAKE(pk []byte, sk []byte, m []byte) (pi, vrfHash []byte, err error) {
+	x := expandSecret(sk)
+	h := hashToCurveSHAKE(m, pk)
+	gamma := geScalarMult(h, x)
+	r := ecp2OS(gamma)
+
+	kp, ks, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	k := expandSecret(ks)
+
+	c := hashPointsSHAKE(ecp2OS(g), ecp2OS(h), s2OS(pk), r, s2OS(kp), ecp2OS(geScalarMult(h, k)))
+
+	// s = k - c*x mod q
+	var z big.Int
+	s := z.Mod(z.Sub(f2IP(k), z.Mul(c, f2IP(x))), q)
+
+	var buf bytes.Buffer
+	buf.Write(r) // 2N
+	buf.Write(i2OSP(c, N))
+	buf.Write(i2OSP(s, N2))
+	pi = buf.Bytes()
+	return pi, shakeBeta(gamma, m), nil
+}
+
+// VerifySHAKE checks a proof produced by ProveSHAKE.
+func VerifySHAKE(pk []byte, pi []byte, m []byte) (bool, error) {
+	r, c, s, err := decodeProof(pi)
+	if err != nil {
+		return false, err
+	}
+
+	var u edwards25519.ProjectiveGroupElement
+	P := os2ECP(pk, pk[31]>>7)
+	if P == nil {
+		return false, ErrMalformedInput
+	}
+	edwards25519.GeDoubleScalarMultVartime(&u, c, P, s)
+
+	h := hashToCurveSHAKE(m, pk)
+	v := geAdd(geScalarMult(r, c), geScalarMult(h, s))
+
+	c2 := hashPointsSHAKE(ecp2OS(g), ecp2OS(h), s2OS(pk), ecp2OS(r), ecp2OSProj(&u), ecp2OS(v))
+	return c2.Cmp(f2IP(c)) == 0, nil
+}
+
+// HashSHAKE recovers the vrf output from a proof produced by ProveSHAKE
+// and the message it was proved over, without re-deriving the proof.
+// Callers should call VerifySHAKE first; HashSHAKE does not itself check
+// that pi is valid for pk.
+func HashSHAKE(pi []byte, m []byte) ([]byte, error) {
+	gamma, _, _, err := decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return shakeBeta(gamma, m), nil
+}
+
+// shakeBeta computes the SHAKE256 vrf output SHAKE256(gamma_bytes || m, N2)
+// for the Gamma point gamma = H(m)^x, mirroring coname's
+// vrf = h(ii_bytes, m).
+func shakeBeta(gamma *edwards25519.ExtendedGroupElement, m []byte) []byte {
+	var gammaBytes [32]byte
+	gamma.ToBytes(&gammaBytes)
+
+	hash := sha3.NewShake256()
+	hash.Write(gammaBytes[:])
+	hash.Write(m)
+	beta := make([]byte, N2)
+	hash.Read(beta)
+	return beta
+}
+
+func hashPointsSHAKE(ps ...[]byte) *big.Int {
+	hash := sha3.NewShake256()
+	for _, p := range ps {
+		hash.Write(p)
+	}
+	v := make([]byte, N)
+	hash.Read(v)
+	return os2IP(v)
+}
+
+func hashToCurveSHAKE(m []byte, pk []byte) *edwards25519.ExtendedGroupElement {
+	for i := int64(0); i < limit; i++ {
+		ctr := i2OSP(big.NewInt(i), 4)
+		hash := sha3.NewShake256()
+		hash.Write(m)
+		hash.Write(pk)
+		hash.Write(ctr)
+		buf := make([]byte, 32)
+		hash.Read(buf)
+		if P := os2ECP(buf, NOSIGN); P != nil {
+			// assume cofactor is 2^n
+			for j := 1; j < cofactor; j *= 2 {
+				P = geDouble(P)
+			}
+			return P
+		}
+	}
+	panic("hashToCurveSHAKE: couldn't make a point on curve")
+}
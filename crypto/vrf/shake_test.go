@@ -0,0 +1,179 @@
+package vrf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+
+	conamevrf "github.com/yahoo/coname/vrf"
+)
+
+func TestShakeProveVerifyRoundTrip(t *testing.T) {
+	pk, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := []byte("shake vrf test message")
+
+	pi, hash, err := ProveSHAKE(pk, sk, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hash) != N2 {
+		t.Fatalf("hash length = %d, want %d", len(hash), N2)
+	}
+
+	ok, err := VerifySHAKE(pk, pi, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("VerifySHAKE rejected its own proof")
+	}
+
+	ok, err = VerifySHAKE(pk, pi, append(m, 0x00))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("VerifySHAKE accepted a proof for the wrong message")
+	}
+}
+
+// TestShakeRegressionVector pins a fixed key/message pair so an
+// accidental change to the SHAKE256 domain separation or point
+// encoding is caught by a byte-for-byte diff rather than a round-trip
+// pass/fail.
+func TestShakeRegressionVector(t *testing.T) {
+	seed, err := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		t.Fatalf("seed length = %d, want %d", len(seed), ed25519.SeedSize)
+	}
+	sk := ed25519.NewKeyFromSeed(seed)
+	pk := sk.Public().(ed25519.PublicKey)
+
+	pi, beta, err := ProveSHAKE(pk, sk, []byte("fixed message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifySHAKE(pk, pi, []byte("fixed message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("VerifySHAKE rejected the fixed regression vector's own proof")
+	}
+
+	want, err := HashSHAKE(pi, []byte("fixed message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(beta, want) {
+		t.Fatalf("ProveSHAKE beta = %x, HashSHAKE recomputed = %x", beta, want)
+	}
+
+	// beta depends only on the key and message (the random nonce only
+	// affects c and s), so it can be pinned.
+	pinned, err := hex.DecodeString("3a0b5cc44e5813105a01f21dfae3b78e5168d382d79a89120a316454fe51f7b9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(beta, pinned) {
+		t.Fatalf("beta = %x, want pinned %x", beta, pinned)
+	}
+}
+
+// TestShakeOutputMatchesSpec recomputes beta = SHAKE256(gamma_bytes || m,
+// N2) directly from the proof's decoded Gamma point, independently of
+// shakeBeta, to confirm ProveSHAKE's vrf output is actually the
+// requested SHAKE256-derived value and not the legacy scheme's
+// Hash(pi) (which is just pi's raw, unhashed Gamma bytes).
+func TestShakeOutputMatchesSpec(t *testing.T) {
+	pk, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := []byte("spec cross-check message")
+
+	pi, beta, err := ProveSHAKE(pk, sk, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gamma, _, _, err := decodeProof(pi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gammaBytes [32]byte
+	gamma.ToBytes(&gammaBytes)
+
+	hash := sha3.NewShake256()
+	hash.Write(gammaBytes[:])
+	hash.Write(m)
+	want := make([]byte, N2)
+	hash.Read(want)
+
+	if !bytes.Equal(beta, want) {
+		t.Fatalf("beta = %x, want SHAKE256(gamma||m) = %x", beta, want)
+	}
+	if bytes.Equal(beta, Hash(pi)) {
+		t.Fatal("beta must not equal the legacy scheme's unhashed Hash(pi)")
+	}
+}
+
+// TestShakeCrossCheckReferenceImplementation exercises the real
+// yahoo/coname/vrf reference implementation side by side with ours.
+// The two are not byte-compatible (coname uses its own edwards25519
+// fork, an Elligator hash-to-curve map and extra25519 point decoding,
+// where this package reuses the legacy scheme's arithmetic), so this
+// only cross-checks that both satisfy the same Prove/Verify contract
+// for the same class of input, not that they produce identical output.
+func TestShakeCrossCheckReferenceImplementation(t *testing.T) {
+	refPK, refSK, err := conamevrf.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := []byte("cross-check message")
+
+	refVRF, refProof := conamevrf.Prove(m, refSK)
+	if !conamevrf.Verify(refPK, m, refVRF, refProof) {
+		t.Fatal("reference yahoo/coname/vrf failed to verify its own proof")
+	}
+
+	pk, sk, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pi, beta, err := ProveSHAKE(pk, sk, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := VerifySHAKE(pk, pi, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("ProveSHAKE/VerifySHAKE failed to verify its own proof")
+	}
+
+	// Both implementations compute their vrf output the same way --
+	// a SHAKE256 digest of (intermediate point bytes || m) -- even
+	// though the point encodings themselves aren't compatible. Confirm
+	// ours actually follows that shape rather than falling back to the
+	// legacy scheme's unhashed Hash(pi).
+	if len(beta) != len(refVRF) {
+		t.Fatalf("beta length = %d, reference vrf length = %d", len(beta), len(refVRF))
+	}
+	if bytes.Equal(beta, Hash(pi)) {
+		t.Fatal("beta must not equal the legacy scheme's unhashed Hash(pi)")
+	}
+	if bytes.Equal(beta, refVRF) {
+		t.Fatal("beta unexpectedly matches the reference implementation's vrf output (point encodings differ, so this should be impossible)")
+	}
+}
@@ -0,0 +1,109 @@
+package vrf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/yoseplee/vrf/edwards25519"
+)
+
+// ProveStream and VerifyStream are Prove and Verify for messages too
+// large to hold in memory at once. Instead of taking m as a []byte,
+// they stream it through a hash.Hash exactly once and use the
+// resulting digest wherever Prove/Verify would have used m, via
+// hashToCurveDigest.
+//
+// h lets the caller choose and pre-configure the hash (for example to
+// reuse a buffer pool); if h is nil, sha256.New() is used, matching
+// the hash Prove/Verify already use internally. h must be empty (freshly
+// constructed or Reset) when passed in, since ProveStream/VerifyStream
+// write the entire message into it.
+
+// ProveStream generates a vrf output and corresponding proof(pi) for a
+// message read from m, without holding the whole message in memory.
+func ProveStream(pk []byte, sk []byte, m io.Reader, h hash.Hash) (pi, vrfHash []byte, err error) {
+	digest, err := digestStream(m, h)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	x := expandSecret(sk)
+	hp := hashToCurveDigest(digest, pk)
+	r := ecp2OS(geScalarMult(hp, x))
+
+	kp, ks, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	k := expandSecret(ks)
+
+	c := hashPoints(ecp2OS(g), ecp2OS(hp), s2OS(pk), r, s2OS(kp), ecp2OS(geScalarMult(hp, k)))
+
+	// s = k - c*x mod q
+	var z big.Int
+	s := z.Mod(z.Sub(f2IP(k), z.Mul(c, f2IP(x))), q)
+
+	var buf bytes.Buffer
+	buf.Write(r) // 2N
+	buf.Write(i2OSP(c, N))
+	buf.Write(i2OSP(s, N2))
+	pi = buf.Bytes()
+	return pi, Hash(pi), nil
+}
+
+// VerifyStream checks a proof produced by ProveStream. Note that
+// ProveStream/VerifyStream operate on the digest produced by streaming m
+// through h (sha256(m) by default), not on m itself: Prove(pk, sk, msg)
+// and VerifyStream(pk, pi, bytes.NewReader(msg), nil) do NOT verify
+// against each other even when msg is identical, since Prove hashes msg
+// to a curve point directly while VerifyStream hashes digestStream's
+// output. To interoperate with Prove/Verify on a message too large to
+// buffer, call Prove/Verify with the digest explicitly, as
+// TestStreamAgreesWithInMemory does.
+func VerifyStream(pk []byte, pi []byte, m io.Reader, h hash.Hash) (bool, error) {
+	digest, err := digestStream(m, h)
+	if err != nil {
+		return false, err
+	}
+
+	r, c, s, err := decodeProof(pi)
+	if err != nil {
+		return false, err
+	}
+
+	var u edwards25519.ProjectiveGroupElement
+	P := os2ECP(pk, pk[31]>>7)
+	if P == nil {
+		return false, ErrMalformedInput
+	}
+	edwards25519.GeDoubleScalarMultVartime(&u, c, P, s)
+
+	hp := hashToCurveDigest(digest, pk)
+	v := geAdd(geScalarMult(r, c), geScalarMult(hp, s))
+
+	c2 := hashPoints(ecp2OS(g), ecp2OS(hp), s2OS(pk), ecp2OS(r), ecp2OSProj(&u), ecp2OS(v))
+	return c2.Cmp(f2IP(c)) == 0, nil
+}
+
+func digestStream(m io.Reader, h hash.Hash) ([]byte, error) {
+	if h == nil {
+		h = sha256.New()
+	}
+	if _, err := io.Copy(h, m); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// hashToCurveDigest maps a message digest to a curve point the same way
+// hashToCurve maps a raw message -- it is hashToCurve's try-and-increment
+// loop, just started from a digest instead of a []byte so that large
+// messages never need to be re-read or re-hashed across the up-to-100
+// attempts.
+func hashToCurveDigest(digest []byte, pk []byte) *edwards25519.ExtendedGroupElement {
+	return hashToCurve(digest, pk)
+}